@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/gosuri/uiprogress"
+)
+
+var (
+	headless            bool
+	headlessInput       string
+	headlessOut         string
+	headlessExt         string
+	headlessConcurrency int
+	headlessLevel       string
+	headlessSize        int
+	headlessMargin      int
+	headlessFg          string
+	headlessBg          string
+	headlessSheetPNG    string
+	headlessSheetPDF    string
+	headlessSheetPage   string
+	headlessSheetCols   int
+	headlessSheetPad    int
+	headlessFilenameTpl string
+	headlessVerify      bool
+	headlessLogoPath    string
+	headlessLogoScale   float64
+	headlessLogoPadding int
+)
+
+func parseFlags() {
+	flag.BoolVar(&headless, "headless", false, "run without the desktop UI, reading a pin list from --input")
+	flag.StringVar(&headlessInput, "input", "", `path to the pin code list file: "name pinCode" lines, or a CSV/TSV with a name,payload,level,size,folder header`)
+	flag.StringVar(&headlessOut, "out", "", "output folder for the generated QR code files")
+	flag.StringVar(&headlessExt, "ext", ".png", "file extension for the generated QR code files")
+	flag.IntVar(&headlessConcurrency, "concurrency", 0, "number of worker goroutines (defaults to the number of CPUs)")
+	flag.StringVar(&headlessLevel, "level", "medium", "QR recovery level: low, medium, high or highest")
+	flag.IntVar(&headlessSize, "size", 256, "QR code size in pixels")
+	flag.IntVar(&headlessMargin, "margin", 4, "quiet-zone margin; 0 disables the border")
+	flag.StringVar(&headlessFg, "fg", "", "foreground color as #rrggbb (default black)")
+	flag.StringVar(&headlessBg, "bg", "", "background color as #rrggbb (default white)")
+	flag.StringVar(&headlessSheetPNG, "sheet-png", "", "also composite every QR code into a labeled contact sheet PNG at this path")
+	flag.StringVar(&headlessSheetPDF, "sheet-pdf", "", "also composite every QR code into a paginated contact sheet PDF at this path")
+	flag.StringVar(&headlessSheetPage, "sheet-page", "A4", "sheet PDF page size: A4 or Letter")
+	flag.IntVar(&headlessSheetCols, "sheet-cols", 5, "QR codes per row on the sheet")
+	flag.IntVar(&headlessSheetPad, "sheet-padding", 20, "cell padding, in pixels, around each QR code on the sheet")
+	flag.StringVar(&headlessFilenameTpl, "filename-template", "", `text/template for output filenames, e.g. "{{.Name}}_{{.Index}}{{.Ext}}" (default "{{.Name}}{{.Ext}}")`)
+	flag.BoolVar(&headlessVerify, "verify", false, "decode each generated PNG/JPEG and flag it as failed if it doesn't scan back to the same payload")
+	flag.StringVar(&headlessLogoPath, "logo", "", "composite this PNG logo into the center of each code (forces recovery level H)")
+	flag.Float64Var(&headlessLogoScale, "logo-scale", 0.2, "fraction of the code the logo occupies, capped at 0.25")
+	flag.IntVar(&headlessLogoPadding, "logo-padding", 12, "white padding, in pixels, around the logo")
+	flag.Parse()
+}
+
+// summaryResult is the machine-readable, per pin code outcome printed on
+// stdout once a headless run finishes.
+type summaryResult struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+}
+
+// summary is the JSON document printed on stdout after a headless run.
+type summary struct {
+	Total   int             `json:"total"`
+	Success int             `json:"success"`
+	Failed  int             `json:"failed"`
+	Results []summaryResult `json:"results"`
+}
+
+// runHeadless lets the tool be driven from scripts and CI by reusing
+// generator.processQRCode without ever creating the Lorca window. Progress is
+// reported on a terminal progress bar as workers finish, and a JSON summary
+// of successes/failures is printed on stdout for machine consumption.
+func runHeadless() {
+	if len(headlessInput) == 0 {
+		log.Fatal("--input is required in --headless mode")
+	}
+	if len(headlessOut) == 0 {
+		log.Fatal("--out is required in --headless mode")
+	}
+
+	content, err := ioutil.ReadFile(headlessInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rows, err := parsePinList(string(content))
+	if err != nil {
+		log.Fatal(err)
+	}
+	lines := len(rows)
+
+	uiprogress.Start()
+	bar := uiprogress.AddBar(lines).AppendCompleted().PrependElapsed()
+	bar.PrependFunc(func(b *uiprogress.Bar) string {
+		return fmt.Sprintf("generating QR codes (%d/%d)", b.Current(), lines)
+	})
+
+	var mu sync.Mutex
+	results := make([]summaryResult, 0, lines)
+
+	opts := defaultRenderOptions()
+	opts.Level = parseRecoveryLevel(headlessLevel)
+	if headlessSize > 0 {
+		opts.Size = headlessSize
+	}
+	if headlessMargin >= 0 {
+		opts.Margin = headlessMargin
+	}
+	var err2 error
+	if opts.ForegroundColor, err2 = parseHexColor(headlessFg, opts.ForegroundColor); err2 != nil {
+		log.Fatal(err2)
+	}
+	if opts.BackgroundColor, err2 = parseHexColor(headlessBg, opts.BackgroundColor); err2 != nil {
+		log.Fatal(err2)
+	}
+	if headlessLogoPath != "" {
+		opts.Logo = &LogoOptions{Path: headlessLogoPath, Scale: headlessLogoScale, Padding: headlessLogoPadding}
+	}
+
+	g := &generator{
+		pinCodeList:      string(content),
+		folder:           headlessOut,
+		fileExt:          headlessExt,
+		concurrency:      headlessConcurrency,
+		opts:             opts,
+		filenameTemplate: headlessFilenameTpl,
+		verify:           headlessVerify,
+		onJobDone: func(valueName string, success bool) {
+			mu.Lock()
+			results = append(results, summaryResult{Name: valueName, Success: success})
+			mu.Unlock()
+			bar.Incr()
+		},
+	}
+
+	if headlessSheetPNG != "" || headlessSheetPDF != "" {
+		g.sheet = newSheetBuilder(SheetOptions{
+			PageSize:    headlessSheetPage,
+			CodesPerRow: headlessSheetCols,
+			CellPadding: headlessSheetPad,
+		})
+		g.sheetOutPNG = headlessSheetPNG
+		g.sheetOutPDF = headlessSheetPDF
+	}
+
+	g.processQRCode()
+	uiprogress.Stop()
+
+	sum := summary{Total: len(results)}
+	for _, r := range results {
+		if r.Success {
+			sum.Success++
+		} else {
+			sum.Failed++
+		}
+	}
+	sum.Results = results
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sum); err != nil {
+		log.Fatal(err)
+	}
+
+	if sum.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// pinRow is one entry from the pin code list, after parsing. Level, Size
+// and Folder are per-row overrides of the batch-wide settings and are only
+// populated when the input has a matching CSV/TSV column.
+type pinRow struct {
+	Name    string
+	Payload string
+	Level   string
+	Size    int
+	Folder  string
+}
+
+// defaultFilenameTemplate reproduces the tool's original
+// folder/name+ext naming when the caller doesn't supply a template.
+const defaultFilenameTemplate = "{{.Name}}{{.Ext}}"
+
+// filenameData is the data available to a filename template.
+type filenameData struct {
+	Name  string
+	Index int
+	Ext   string
+}
+
+// renderFilename executes tmpl (parsed once per batch via
+// compileFilenameTemplate) for a single pin code entry.
+func renderFilename(tmpl *template.Template, data filenameData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render filename: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func compileFilenameTemplate(src string) (*template.Template, error) {
+	if src == "" {
+		src = defaultFilenameTemplate
+	}
+	return template.New("filename").Parse(src)
+}
+
+// parsePinList turns the raw pin list textarea content into rows. A
+// comma or tab on the first line is treated as a CSV/TSV header naming
+// some of name, payload (or pincode), level, size, folder; any other
+// columns are ignored. Without a recognized header it falls back to the
+// original "name payload" whitespace-separated format (or a bare value
+// used as both), so existing pin lists keep working unchanged.
+func parsePinList(content string) ([]pinRow, error) {
+	content = strings.TrimRight(content, "\r\n")
+	if content == "" {
+		return nil, nil
+	}
+	lines := strings.Split(content, "\n")
+
+	switch detectDelimiter(lines[0]) {
+	case ',':
+		return parseDelimitedPinList(content, ',')
+	case '\t':
+		return parseDelimitedPinList(content, '\t')
+	default:
+		return parseLegacyPinList(lines), nil
+	}
+}
+
+func detectDelimiter(headerLine string) rune {
+	switch {
+	case strings.Contains(headerLine, "\t"):
+		return '\t'
+	case strings.Contains(headerLine, ","):
+		return ','
+	default:
+		return 0
+	}
+}
+
+func parseDelimitedPinList(content string, delim rune) ([]pinRow, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = delim
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse pin list: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col := make(map[string]int, len(records[0]))
+	for i, h := range records[0] {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	nameIdx, hasName := col["name"]
+	payloadIdx, hasPayload := col["payload"]
+	if !hasPayload {
+		payloadIdx, hasPayload = col["pincode"]
+	}
+	if !hasName || !hasPayload {
+		// Not actually a recognized header row - treat every line as data.
+		return parseLegacyPinList(strings.Split(content, "\n")), nil
+	}
+	levelIdx, hasLevel := col["level"]
+	sizeIdx, hasSize := col["size"]
+	folderIdx, hasFolder := col["folder"]
+
+	rows := make([]pinRow, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		if isBlankRecord(rec) {
+			continue
+		}
+
+		row := pinRow{Name: field(rec, nameIdx), Payload: field(rec, payloadIdx)}
+		if hasLevel {
+			row.Level = field(rec, levelIdx)
+		}
+		if hasFolder {
+			row.Folder = field(rec, folderIdx)
+		}
+		if hasSize {
+			if s := field(rec, sizeIdx); s != "" {
+				if n, err := strconv.Atoi(s); err == nil {
+					row.Size = n
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseLegacyPinList(lines []string) []pinRow {
+	rows := make([]pinRow, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, " ")
+		var row pinRow
+		switch len(parts) {
+		case 1:
+			row.Name, row.Payload = parts[0], parts[0]
+		case 2:
+			row.Name, row.Payload = parts[0], parts[1]
+		default:
+			fmt.Println("value format is error")
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func field(rec []string, idx int) string {
+	if idx < 0 || idx >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[idx])
+}
+
+func isBlankRecord(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
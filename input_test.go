@@ -0,0 +1,122 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePinListLegacySingleToken(t *testing.T) {
+	rows, err := parsePinList("1001\n1002")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{
+		{Name: "1001", Payload: "1001"},
+		{Name: "1002", Payload: "1002"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListLegacyNameAndPayload(t *testing.T) {
+	rows, err := parsePinList("alice 1001\nbob 1002")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{
+		{Name: "alice", Payload: "1001"},
+		{Name: "bob", Payload: "1002"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListLegacySkipsMalformedLines(t *testing.T) {
+	rows, err := parsePinList("alice 1001 extra\nbob 1002")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{{Name: "bob", Payload: "1002"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListCSVHeader(t *testing.T) {
+	rows, err := parsePinList("name,payload,level,size,folder\nalice,1001,high,512,vip\nbob,1002,,,")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{
+		{Name: "alice", Payload: "1001", Level: "high", Size: 512, Folder: "vip"},
+		{Name: "bob", Payload: "1002"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListCSVAcceptsPincodeColumn(t *testing.T) {
+	rows, err := parsePinList("name,pincode\nalice,1001")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{{Name: "alice", Payload: "1001"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListTSVHeader(t *testing.T) {
+	rows, err := parsePinList("name\tpayload\nalice\t1001")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{{Name: "alice", Payload: "1001"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListCSVWithoutRecognizedHeaderFallsBackToLegacy(t *testing.T) {
+	rows, err := parsePinList("foo,bar\nalice,1001")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{{Name: "foo,bar", Payload: "foo,bar"}, {Name: "alice,1001", Payload: "alice,1001"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListCSVSkipsBlankRows(t *testing.T) {
+	rows, err := parsePinList("name,payload\nalice,1001\n,\nbob,1002")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	want := []pinRow{
+		{Name: "alice", Payload: "1001"},
+		{Name: "bob", Payload: "1002"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("parsePinList() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePinListEmptyInput(t *testing.T) {
+	rows, err := parsePinList("")
+	if err != nil {
+		t.Fatalf("parsePinList() error = %v", err)
+	}
+	if rows != nil {
+		t.Errorf("parsePinList(\"\") = %+v, want nil", rows)
+	}
+}
+
+func TestParsePinListMalformedCSVErrors(t *testing.T) {
+	if _, err := parsePinList("name,payload\n\"unterminated,1001"); err == nil {
+		t.Error("parsePinList() with malformed CSV: want error, got nil")
+	}
+}
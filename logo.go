@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+
+	ximagedraw "golang.org/x/image/draw"
+)
+
+// LogoOptions configures a caller-supplied logo composited into the center
+// of a generated QR code. Enabling it forces the recovery level to H (see
+// newQR in renderer.go) since covering part of the code only stays
+// scannable at the highest error-correction level.
+type LogoOptions struct {
+	Path    string
+	Scale   float64 // fraction of the code's width the logo occupies; capped at 0.25, default 0.2
+	Padding int     // white padding, in pixels, around the logo before compositing
+}
+
+// overlayLogo decodes the PNG at opts.Path and draws it, centered on a
+// white rounded-rect background, on top of qr.
+func overlayLogo(qr image.Image, opts *LogoOptions) (image.Image, error) {
+	logoFile, err := os.Open(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("logo: %w", err)
+	}
+	defer logoFile.Close()
+
+	logoImg, err := png.Decode(logoFile)
+	if err != nil {
+		return nil, fmt.Errorf("logo: %w", err)
+	}
+
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = 0.2
+	}
+	if scale > 0.25 {
+		scale = 0.25
+	}
+
+	bounds := qr.Bounds()
+	logoSize := int(float64(bounds.Dx()) * scale)
+
+	resizedLogo := image.NewRGBA(image.Rect(0, 0, logoSize, logoSize))
+	ximagedraw.CatmullRom.Scale(resizedLogo, resizedLogo.Bounds(), logoImg, logoImg.Bounds(), ximagedraw.Over, nil)
+
+	out := image.NewRGBA(bounds)
+	draw.Draw(out, bounds, qr, bounds.Min, draw.Src)
+
+	padding := opts.Padding
+	if padding < 0 {
+		padding = 0
+	}
+	bgSize := logoSize + padding*2
+	bgRect := centeredRect(bounds, bgSize)
+	fillRoundedRect(out, bgRect, bgSize/6, color.White)
+
+	draw.Draw(out, centeredRect(bounds, logoSize), resizedLogo, image.Point{}, draw.Over)
+
+	return out, nil
+}
+
+func centeredRect(bounds image.Rectangle, size int) image.Rectangle {
+	cx, cy := bounds.Min.X+bounds.Dx()/2, bounds.Min.Y+bounds.Dy()/2
+	half := size / 2
+	return image.Rect(cx-half, cy-half, cx-half+size, cy-half+size)
+}
+
+// fillRoundedRect fills rect with c, rounding each corner by radius pixels.
+func fillRoundedRect(dst draw.Image, rect image.Rectangle, radius int, c color.Color) {
+	if radius <= 0 {
+		draw.Draw(dst, rect, image.NewUniform(c), image.Point{}, draw.Over)
+		return
+	}
+
+	left, top, right, bottom := rect.Min.X, rect.Min.Y, rect.Max.X-1, rect.Max.Y-1
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			var cornerX, cornerY int
+			switch {
+			case x < left+radius && y < top+radius:
+				cornerX, cornerY = left+radius, top+radius
+			case x > right-radius && y < top+radius:
+				cornerX, cornerY = right-radius, top+radius
+			case x < left+radius && y > bottom-radius:
+				cornerX, cornerY = left+radius, bottom-radius
+			case x > right-radius && y > bottom-radius:
+				cornerX, cornerY = right-radius, bottom-radius
+			default:
+				dst.Set(x, y, c)
+				continue
+			}
+
+			dx, dy := x-cornerX, y-cornerY
+			if dx*dx+dy*dy <= radius*radius {
+				dst.Set(x, y, c)
+			}
+		}
+	}
+}
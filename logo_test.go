@@ -0,0 +1,26 @@
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCenteredRect(t *testing.T) {
+	bounds := image.Rect(0, 0, 100, 100)
+
+	got := centeredRect(bounds, 20)
+	want := image.Rect(40, 40, 60, 60)
+	if got != want {
+		t.Errorf("centeredRect(%v, 20) = %v, want %v", bounds, got, want)
+	}
+}
+
+func TestCenteredRectWithOffsetBounds(t *testing.T) {
+	bounds := image.Rect(10, 10, 110, 110)
+
+	got := centeredRect(bounds, 20)
+	want := image.Rect(50, 50, 70, 70)
+	if got != want {
+		t.Errorf("centeredRect(%v, 20) = %v, want %v", bounds, got, want)
+	}
+}
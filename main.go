@@ -10,14 +10,22 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
-	"strings"
 	"sync"
+	"text/template"
 
-	"github.com/skip2/go-qrcode"
 	"github.com/zserge/lorca"
+
+	"github.com/water25234/golang-gui-genQRCode/payload"
 )
 
 func main() {
+	parseFlags()
+
+	if headless {
+		runHeadless()
+		return
+	}
+
 	args := []string{}
 	if runtime.GOOS == "linux" {
 		args = append(args, "--class=Lorca")
@@ -36,6 +44,7 @@ func main() {
 	// Create and bind Go object to the UI
 	g := &generator{}
 	ui.Bind("generatorQRCode", g.generatorQRCode)
+	ui.Bind("generateSheet", g.generateSheet)
 
 	// Load HTML.
 	// You may also use `data:text/html,<base64>` approach to load initial HTML,
@@ -83,6 +92,29 @@ type generator struct {
 	pinCodeList string
 	folder      string
 	fileExt     string
+	concurrency int
+	opts        RenderOptions
+
+	// filenameTemplate renders each output path from a name/index/extension;
+	// defaultFilenameTemplate is used when this is left empty.
+	filenameTemplate string
+
+	// verify, when set, decodes each freshly written raster QR code and
+	// flags a mismatch against the source payload into errLog instead of
+	// only trusting that WriteFile/Render returned no error.
+	verify bool
+
+	// sheet, when set, makes work additionally collect each rendered QR code
+	// for a single composited contact sheet, written to sheetOutPNG and/or
+	// sheetOutPDF once the whole batch finishes.
+	sheet       *sheetBuilder
+	sheetOutPNG string
+	sheetOutPDF string
+
+	// onJobDone, when set, is invoked after every pin code has been processed
+	// (successfully or not). It lets callers such as the headless CLI drive a
+	// progress bar without the GUI binding needing to know about it.
+	onJobDone func(valueName string, success bool)
 }
 
 type errLog struct {
@@ -90,11 +122,25 @@ type errLog struct {
 }
 
 type jobChannel struct {
-	index       int
-	fileContent string
+	index int
+	row   pinRow
 }
 
-func (g *generator) generatorQRCode(pinCodeList string, folder string, fileExt string) (result string, err error) {
+// generatorQRCode is bound to the Lorca UI. level, size and margin control
+// the underlying go-qrcode encoding (recovery level L/M/Q/H, pixel size and
+// quiet-zone margin); foregroundColor/backgroundColor are "#rrggbb" strings.
+// Branded, print-ready codes typically want level "H" so a centered logo
+// overlay or ink smudging doesn't break scanning. pinCodeList may be a plain
+// "name payload" list or a CSV/TSV with a name,payload,level,size,folder
+// header, in which case a row's columns override the batch-wide settings.
+// filenameTemplate is a text/template string (e.g. "{{.Name}}_{{.Index}}{{.Ext}}")
+// and defaults to "{{.Name}}{{.Ext}}" when empty. verify, for PNG/JPEG
+// output, decodes each freshly written code and flags it as a failure if it
+// doesn't scan back to the same payload. logoPath, when non-empty,
+// composites that PNG logo into the center of each code (logoScale is the
+// fraction of the code it should occupy, capped at 0.25; logoPadding is the
+// white margin, in pixels, kept around it) and forces recovery level H.
+func (g *generator) generatorQRCode(pinCodeList string, folder string, fileExt string, level string, size int, margin int, foregroundColor string, backgroundColor string, filenameTemplate string, verify bool, logoPath string, logoScale float64, logoPadding int) (result string, err error) {
 	g.Lock()
 	defer g.Unlock()
 	if len(pinCodeList) == 0 {
@@ -109,9 +155,73 @@ func (g *generator) generatorQRCode(pinCodeList string, folder string, fileExt s
 		return "", fmt.Errorf("flags fileExt is empty")
 	}
 
+	opts := defaultRenderOptions()
+	opts.Level = parseRecoveryLevel(level)
+	if size > 0 {
+		opts.Size = size
+	}
+	if margin >= 0 {
+		opts.Margin = margin
+	}
+	if opts.ForegroundColor, err = parseHexColor(foregroundColor, opts.ForegroundColor); err != nil {
+		return "", err
+	}
+	if opts.BackgroundColor, err = parseHexColor(backgroundColor, opts.BackgroundColor); err != nil {
+		return "", err
+	}
+	if logoPath != "" {
+		opts.Logo = &LogoOptions{Path: logoPath, Scale: logoScale, Padding: logoPadding}
+	}
+
 	g.pinCodeList = pinCodeList
 	g.folder = folder
 	g.fileExt = fileExt
+	g.opts = opts
+	g.filenameTemplate = filenameTemplate
+	g.verify = verify
+
+	result = g.processQRCode()
+	return result, nil
+}
+
+// generateSheet is bound to the Lorca UI's "sheet mode": alongside the usual
+// one file per pin code, it composites every generated QR code
+// into a single labeled contact sheet, as a PNG and/or a paginated PDF grid
+// sized for a print run. pngPath/pdfPath may be left empty to skip that
+// output.
+func (g *generator) generateSheet(pinCodeList string, folder string, fileExt string, level string, size int, pngPath string, pdfPath string, pageSize string, codesPerRow int, cellPadding int) (result string, err error) {
+	g.Lock()
+	defer g.Unlock()
+	if len(pinCodeList) == 0 {
+		return "", fmt.Errorf("flags readfile is empty")
+	}
+	if len(folder) == 0 {
+		return "", fmt.Errorf("flags folder is empty")
+	}
+	if len(fileExt) == 0 {
+		return "", fmt.Errorf("flags fileExt is empty")
+	}
+	if len(pngPath) == 0 && len(pdfPath) == 0 {
+		return "", fmt.Errorf("sheet mode needs at least one of pngPath or pdfPath")
+	}
+
+	opts := defaultRenderOptions()
+	opts.Level = parseRecoveryLevel(level)
+	if size > 0 {
+		opts.Size = size
+	}
+
+	g.pinCodeList = pinCodeList
+	g.folder = folder
+	g.fileExt = fileExt
+	g.opts = opts
+	g.sheet = newSheetBuilder(SheetOptions{
+		PageSize:    pageSize,
+		CodesPerRow: codesPerRow,
+		CellPadding: cellPadding,
+	})
+	g.sheetOutPNG = pngPath
+	g.sheetOutPDF = pdfPath
 
 	result = g.processQRCode()
 	return result, nil
@@ -120,34 +230,50 @@ func (g *generator) generatorQRCode(pinCodeList string, folder string, fileExt s
 func (g *generator) processQRCode() (result string) {
 	fmt.Println("--------------- start work ---------------")
 
-	fileContentArr := strings.Split(g.pinCodeList, "\n")
-	fileContentCount := len(fileContentArr)
+	rows, err := parsePinList(g.pinCodeList)
+	if err != nil {
+		fmt.Println("parse pin list failure", err)
+		return fmt.Sprintf("剖析名單失敗： %v", err)
+	}
+
+	tmpl, err := compileFilenameTemplate(g.filenameTemplate)
+	if err != nil {
+		fmt.Println("parse filename template failure", err)
+		return fmt.Sprintf("剖析檔名樣板失敗： %v", err)
+	}
+
+	rowCount := len(rows)
 	errGenQRCode := &errLog{}
 
 	os.MkdirAll(g.folder, os.ModePerm)
 
 	// channel for job
-	jobChans := make(chan jobChannel, fileContentCount)
+	jobChans := make(chan jobChannel, rowCount)
 
 	// start workers
 	wg := &sync.WaitGroup{}
-	wg.Add(fileContentCount)
+	wg.Add(rowCount)
+
+	workerCount := g.concurrency
+	if workerCount <= 0 {
+		workerCount = runtime.NumCPU()
+	}
 
 	// start workers
-	for i := 1; i <= runtime.NumCPU(); i++ {
+	for i := 1; i <= workerCount; i++ {
 		go func(i int) {
 			for job := range jobChans {
-				g.work(job.fileContent, errGenQRCode)
+				g.work(job.row, job.index, tmpl, errGenQRCode)
 				wg.Done()
 			}
 		}(i)
 	}
 
 	// collect job
-	for i := 0; i < fileContentCount; i++ {
+	for i, row := range rows {
 		jobChans <- jobChannel{
-			index:       i,
-			fileContent: fileContentArr[i],
+			index: i,
+			row:   row,
 		}
 	}
 
@@ -159,6 +285,19 @@ func (g *generator) processQRCode() (result string) {
 		fmt.Println("error gen qr code failure list : ", errGenQRCode.errGenQRCode)
 	}
 
+	if g.sheet != nil {
+		if g.sheetOutPNG != "" {
+			if err := g.sheet.WritePNG(g.sheetOutPNG); err != nil {
+				fmt.Println("gen sheet PNG failure", err)
+			}
+		}
+		if g.sheetOutPDF != "" {
+			if err := g.sheet.WritePDF(g.sheetOutPDF); err != nil {
+				fmt.Println("gen sheet PDF failure", err)
+			}
+		}
+	}
+
 	fmt.Println("--------------- finish work ---------------")
 	return fmt.Sprintf("執行完成，請找資料夾 『 %s 』 並且確認檔案數量與內容", g.folder)
 }
@@ -172,35 +311,51 @@ func (g *generator) fileSize(pingCode string) (size int64, err error) {
 	return fi.Size(), nil
 }
 
-func (g *generator) pinCodeInfo(valueArr []string) (valueName string, valuePinCode string, err error) {
-	if len(valueArr) == 1 {
-		valueName = valueArr[0]
-		valuePinCode = valueArr[0]
-	} else if len(valueArr) == 2 {
-		valueName = valueArr[0]
-		valuePinCode = valueArr[1]
-	} else {
-		fmt.Println("value format is error")
-		return "", "", fmt.Errorf("value format is error")
-	}
-	return valueName, valuePinCode, nil
-}
-
-func (g *generator) work(fileContent string, errGenQRCode *errLog) {
+func (g *generator) work(row pinRow, index int, tmpl *template.Template, errGenQRCode *errLog) {
 
-	if len(fileContent) == 0 {
+	if len(row.Name) == 0 && len(row.Payload) == 0 {
 		return
 	}
 
-	valueArr := strings.Split(strings.TrimSpace(fileContent), " ")
-	valueName, valuePinCode, err := g.pinCodeInfo(valueArr)
+	valueName := row.Name
+	success := false
+	defer func() {
+		if g.onJobDone != nil {
+			g.onJobDone(valueName, success)
+		}
+	}()
+
+	valuePinCode, err := payload.Resolve(row.Payload)
 	if err != nil {
+		fmt.Println("resolve payload failure", valueName, err)
+		errGenQRCode.errGenQRCode = append(errGenQRCode.errGenQRCode, valueName)
 		return
 	}
 
-	pingCode := g.folder + "/" + valueName + g.fileExt
+	opts := g.opts
+	if row.Level != "" {
+		opts.Level = parseRecoveryLevel(row.Level)
+	}
+	if row.Size > 0 {
+		opts.Size = row.Size
+	}
 
-	err = qrcode.WriteFile(valuePinCode, qrcode.Medium, 256, pingCode)
+	folder := g.folder
+	if row.Folder != "" {
+		folder = g.folder + "/" + row.Folder
+		os.MkdirAll(folder, os.ModePerm)
+	}
+
+	filename, err := renderFilename(tmpl, filenameData{Name: valueName, Index: index, Ext: g.fileExt})
+	if err != nil {
+		fmt.Println("render filename failure", valueName, err)
+		errGenQRCode.errGenQRCode = append(errGenQRCode.errGenQRCode, valueName)
+		return
+	}
+	pingCode := folder + "/" + filename
+
+	renderer := rendererForExt(g.fileExt)
+	err = renderer.Render(valuePinCode, opts, pingCode)
 
 	if err != nil {
 		fmt.Println("gen QR Code failure", pingCode)
@@ -216,5 +371,31 @@ func (g *generator) work(fileContent string, errGenQRCode *errLog) {
 	}
 
 	fmt.Println(fmt.Sprintf("file: %s, file size: %d", pingCode, size))
+	success = true
+
+	if g.verify && verifiableExt(g.fileExt) {
+		if err := verifyQRCode(pingCode, valuePinCode); err != nil {
+			fmt.Println("verify QR Code failure", pingCode, err)
+			errGenQRCode.errGenQRCode = append(errGenQRCode.errGenQRCode, pingCode)
+			success = false
+		}
+	}
+
+	if g.sheet != nil {
+		qr, err := newQR(valuePinCode, opts)
+		if err != nil {
+			fmt.Println("gen sheet entry failure", pingCode)
+			return
+		}
+		img := qr.Image(opts.Size)
+		if opts.Logo != nil {
+			if img, err = overlayLogo(img, opts.Logo); err != nil {
+				fmt.Println("gen sheet entry failure", pingCode)
+				return
+			}
+		}
+		g.sheet.add(valueName, img)
+	}
+
 	return
 }
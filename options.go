@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// defaultRenderOptions mirrors the values the tool has always used
+// (medium recovery, 256px, bordered, black on white), so callers that
+// don't set anything keep today's behavior.
+func defaultRenderOptions() RenderOptions {
+	return RenderOptions{
+		Level:           qrcode.Medium,
+		Size:            256,
+		Margin:          4,
+		ForegroundColor: color.RGBA{0, 0, 0, 255},
+		BackgroundColor: color.RGBA{255, 255, 255, 255},
+	}
+}
+
+// parseRecoveryLevel accepts both the single-letter codes (L/M/Q/H) and
+// their full names (low/medium/high/highest), case-insensitive, and falls
+// back to Medium for anything else, matching the level the tool used
+// before it was configurable.
+func parseRecoveryLevel(level string) qrcode.RecoveryLevel {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "L", "LOW":
+		return qrcode.Low
+	case "Q", "HIGH":
+		return qrcode.High
+	case "H", "HIGHEST":
+		return qrcode.Highest
+	case "M", "MEDIUM", "":
+		return qrcode.Medium
+	default:
+		return qrcode.Medium
+	}
+}
+
+// parseHexColor parses a "#rrggbb" or "rrggbb" string into an opaque
+// color.RGBA. An empty string returns fallback unchanged.
+func parseHexColor(hex string, fallback color.RGBA) (color.RGBA, error) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if hex == "" {
+		return fallback, nil
+	}
+	if len(hex) != 6 {
+		return fallback, fmt.Errorf("color %q must be 6 hex digits", hex)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fallback, fmt.Errorf("color %q is not valid hex: %w", hex, err)
+	}
+
+	return color.RGBA{
+		R: uint8(v >> 16),
+		G: uint8(v >> 8),
+		B: uint8(v),
+		A: 255,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+)
+
+func TestParseRecoveryLevel(t *testing.T) {
+	cases := map[string]qrcode.RecoveryLevel{
+		"":        qrcode.Medium,
+		"l":       qrcode.Low,
+		"LOW":     qrcode.Low,
+		"m":       qrcode.Medium,
+		"MEDIUM":  qrcode.Medium,
+		"q":       qrcode.High,
+		"Q":       qrcode.High,
+		"high":    qrcode.High,
+		"HIGH":    qrcode.High,
+		"h":       qrcode.Highest,
+		"HIGHEST": qrcode.Highest,
+		"bogus":   qrcode.Medium,
+	}
+	for in, want := range cases {
+		if got := parseRecoveryLevel(in); got != want {
+			t.Errorf("parseRecoveryLevel(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	fallback := color.RGBA{1, 2, 3, 255}
+
+	if got, err := parseHexColor("", fallback); err != nil || got != fallback {
+		t.Errorf("parseHexColor(\"\", fallback) = %v, %v, want %v, nil", got, err, fallback)
+	}
+
+	want := color.RGBA{R: 0xff, G: 0x00, B: 0x80, A: 255}
+	for _, in := range []string{"ff0080", "#ff0080"} {
+		got, err := parseHexColor(in, fallback)
+		if err != nil {
+			t.Fatalf("parseHexColor(%q) error = %v", in, err)
+		}
+		if got != want {
+			t.Errorf("parseHexColor(%q) = %v, want %v", in, got, want)
+		}
+	}
+
+	if _, err := parseHexColor("zzzzzz", fallback); err == nil {
+		t.Error("parseHexColor(\"zzzzzz\"): want error, got nil")
+	}
+	if _, err := parseHexColor("abc", fallback); err == nil {
+		t.Error("parseHexColor(\"abc\"): want error, got nil")
+	}
+}
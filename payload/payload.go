@@ -0,0 +1,175 @@
+// Package payload builds the canonical text encodings phone scanners expect
+// for common QR content types, so a pin list row can describe a URL, vCard,
+// Wi-Fi network, TOTP enrollment, geo location, SMS or email instead of only
+// an opaque string.
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// spec is the typed JSON shape accepted as a pin-list row's payload, e.g.
+// {"type":"wifi","ssid":"...","psk":"..."}. Fields are shared across types
+// and only the ones relevant to Type are read.
+type spec struct {
+	Type string `json:"type"`
+
+	URL string `json:"url"`
+
+	Name  string `json:"name"`
+	Org   string `json:"org"`
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+
+	SSID   string `json:"ssid"`
+	PSK    string `json:"psk"`
+	Auth   string `json:"auth"` // WPA, WEP or nopass
+	Hidden bool   `json:"hidden"`
+
+	Issuer  string `json:"issuer"`
+	Account string `json:"account"`
+	Secret  string `json:"secret"`
+
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+
+	Number  string `json:"number"`
+	Message string `json:"message"`
+
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Resolve turns a raw pin-list payload value into the literal text that
+// should be encoded into the QR code. A value that looks like a JSON object
+// (e.g. {"type":"wifi","ssid":"...","psk":"..."}) is decoded and routed to
+// the matching typed builder below; anything else is returned unchanged so
+// plain PIN strings and URLs keep working.
+func Resolve(raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if !strings.HasPrefix(trimmed, "{") {
+		return raw, nil
+	}
+
+	var s spec
+	if err := json.Unmarshal([]byte(trimmed), &s); err != nil {
+		return "", fmt.Errorf("payload: invalid JSON payload: %w", err)
+	}
+
+	switch strings.ToLower(s.Type) {
+	case "url":
+		return URL(s.URL), nil
+	case "vcard":
+		return VCard(s.Name, s.Org, s.Phone, s.Email), nil
+	case "wifi":
+		return WiFi(s.SSID, s.PSK, s.Auth, s.Hidden), nil
+	case "totp":
+		return TOTP(s.Issuer, s.Account, s.Secret), nil
+	case "geo":
+		return Geo(s.Lat, s.Lon), nil
+	case "sms":
+		return SMS(s.Number, s.Message), nil
+	case "mailto":
+		return Mailto(s.Email, s.Subject, s.Body), nil
+	default:
+		return "", fmt.Errorf("payload: unknown type %q", s.Type)
+	}
+}
+
+// URL returns u unchanged; it exists so callers can build a {"type":"url",...}
+// spec alongside the other typed builders instead of special-casing URLs.
+func URL(u string) string {
+	return u
+}
+
+// escapeField backslash-escapes the characters MECARD and WIFI: reserve as
+// field delimiters (";", ",", ":", "\") so a value containing one of them
+// (e.g. an SSID or name with a semicolon) doesn't truncate the field or
+// bleed into the next one.
+func escapeField(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`)
+	return r.Replace(s)
+}
+
+// VCard builds a MECARD contact encoding, the compact format phone camera
+// apps scan directly into a new contact.
+func VCard(name, org, phone, email string) string {
+	var b strings.Builder
+	b.WriteString("MECARD:N:")
+	b.WriteString(escapeField(name))
+	b.WriteString(";")
+	if org != "" {
+		b.WriteString("ORG:" + escapeField(org) + ";")
+	}
+	if phone != "" {
+		b.WriteString("TEL:" + escapeField(phone) + ";")
+	}
+	if email != "" {
+		b.WriteString("EMAIL:" + escapeField(email) + ";")
+	}
+	b.WriteString(";")
+	return b.String()
+}
+
+// WiFi builds the "WIFI:T:...;S:...;P:...;H:...;;" encoding Android and iOS both
+// recognize for joining a network straight from the camera. auth defaults
+// to "WPA" when empty; use "nopass" for an open network.
+func WiFi(ssid, psk, auth string, hidden bool) string {
+	if auth == "" {
+		auth = "WPA"
+	}
+	hiddenStr := "false"
+	if hidden {
+		hiddenStr = "true"
+	}
+	return fmt.Sprintf("WIFI:T:%s;S:%s;P:%s;H:%s;;", auth, escapeField(ssid), escapeField(psk), hiddenStr)
+}
+
+// TOTP builds an "otpauth://totp/..." URI matching the QR-based enrollment
+// pattern used by authenticator apps.
+func TOTP(issuer, account, secret string) string {
+	label := account
+	if issuer != "" {
+		label = issuer + ":" + account
+	}
+
+	q := url.Values{}
+	q.Set("secret", secret)
+	if issuer != "" {
+		q.Set("issuer", issuer)
+	}
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", url.PathEscape(label), q.Encode())
+}
+
+// Geo builds a "geo:lat,lon" URI.
+func Geo(lat, lon float64) string {
+	return fmt.Sprintf("geo:%g,%g", lat, lon)
+}
+
+// SMS builds an "SMSTO:number:message" encoding that opens the messaging
+// app with the number and body pre-filled.
+func SMS(number, message string) string {
+	if message == "" {
+		return fmt.Sprintf("SMSTO:%s", number)
+	}
+	return fmt.Sprintf("SMSTO:%s:%s", number, message)
+}
+
+// Mailto builds a "mailto:" URI with an optional subject/body.
+func Mailto(address, subject, body string) string {
+	q := url.Values{}
+	if subject != "" {
+		q.Set("subject", subject)
+	}
+	if body != "" {
+		q.Set("body", body)
+	}
+	if len(q) == 0 {
+		return "mailto:" + address
+	}
+	return fmt.Sprintf("mailto:%s?%s", address, q.Encode())
+}
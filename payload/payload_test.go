@@ -0,0 +1,91 @@
+package payload
+
+import "testing"
+
+func TestVCardEscapesReservedCharacters(t *testing.T) {
+	got := VCard(`Smith;Jones`, "", "", "")
+	want := `MECARD:N:Smith\;Jones;;`
+	if got != want {
+		t.Errorf("VCard() = %q, want %q", got, want)
+	}
+}
+
+func TestWiFiEscapesReservedCharacters(t *testing.T) {
+	got := WiFi(`Smith;Jones`, `pa,ss:word\`, "", false)
+	want := `WIFI:T:WPA;S:Smith\;Jones;P:pa\,ss\:word\\;H:false;;`
+	if got != want {
+		t.Errorf("WiFi() = %q, want %q", got, want)
+	}
+}
+
+func TestWiFiDefaultsAuthToWPA(t *testing.T) {
+	got := WiFi("guest", "", "", true)
+	want := "WIFI:T:WPA;S:guest;P:;H:true;;"
+	if got != want {
+		t.Errorf("WiFi() = %q, want %q", got, want)
+	}
+}
+
+func TestResolvePlainStringPassesThrough(t *testing.T) {
+	got, err := Resolve("12345")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "12345" {
+		t.Errorf("Resolve() = %q, want %q", got, "12345")
+	}
+}
+
+func TestResolveDispatchesByType(t *testing.T) {
+	got, err := Resolve(`{"type":"wifi","ssid":"home","psk":"secret"}`)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := "WIFI:T:WPA;S:home;P:secret;H:false;;"
+	if got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveUnknownTypeErrors(t *testing.T) {
+	if _, err := Resolve(`{"type":"bogus"}`); err == nil {
+		t.Error("Resolve() with unknown type: want error, got nil")
+	}
+}
+
+func TestResolveInvalidJSONErrors(t *testing.T) {
+	if _, err := Resolve(`{"type":`); err == nil {
+		t.Error("Resolve() with malformed JSON: want error, got nil")
+	}
+}
+
+func TestTOTPIncludesIssuerInLabelAndQuery(t *testing.T) {
+	got := TOTP("Example", "alice@example.com", "SECRET")
+	want := "otpauth://totp/Example:alice@example.com?issuer=Example&secret=SECRET"
+	if got != want {
+		t.Errorf("TOTP() = %q, want %q", got, want)
+	}
+}
+
+func TestGeoFormatsLatLon(t *testing.T) {
+	got := Geo(35.6812, 139.7671)
+	want := "geo:35.6812,139.7671"
+	if got != want {
+		t.Errorf("Geo() = %q, want %q", got, want)
+	}
+}
+
+func TestSMSOmitsMessageWhenEmpty(t *testing.T) {
+	if got := SMS("555-1234", ""); got != "SMSTO:555-1234" {
+		t.Errorf("SMS() = %q, want %q", got, "SMSTO:555-1234")
+	}
+	if got := SMS("555-1234", "hi"); got != "SMSTO:555-1234:hi" {
+		t.Errorf("SMS() = %q, want %q", got, "SMSTO:555-1234:hi")
+	}
+}
+
+func TestMailtoOmitsQueryWhenEmpty(t *testing.T) {
+	if got := Mailto("a@b.com", "", ""); got != "mailto:a@b.com" {
+		t.Errorf("Mailto() = %q, want %q", got, "mailto:a@b.com")
+	}
+}
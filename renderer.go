@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// RenderOptions carries the knobs a caller (the Lorca UI or the headless
+// CLI) can set for a single QR code, on top of its recovery level.
+type RenderOptions struct {
+	Level           qrcode.RecoveryLevel
+	Size            int
+	Margin          int
+	ForegroundColor color.RGBA
+	BackgroundColor color.RGBA
+
+	// Logo, when set, composites a caller-supplied logo into the center of
+	// the code (PNG/JPEG output only); see overlayLogo in logo.go.
+	Logo *LogoOptions
+}
+
+// Renderer writes a single QR code, generated for the given content, to
+// path under the requested options. Implementations are looked up by the
+// output file extension so the same pin list can be rendered to whichever
+// format a workflow needs.
+type Renderer interface {
+	Render(content string, opts RenderOptions, path string) error
+}
+
+// rendererForExt maps a file extension (as supplied via fileExt, and
+// surfaced in the Lorca UI as a format dropdown) to the Renderer that
+// produces it. Unknown extensions fall back to PNG to preserve the
+// tool's original behavior.
+func rendererForExt(ext string) Renderer {
+	switch strings.ToLower(ext) {
+	case ".jpg", ".jpeg":
+		return jpegRenderer{}
+	case ".svg":
+		return svgRenderer{}
+	case ".pdf":
+		return pdfRenderer{}
+	case ".txt", ".ascii":
+		return asciiRenderer{}
+	default:
+		return pngRenderer{}
+	}
+}
+
+// newQR builds a qrcode.QRCode with the recovery level, colors and margin
+// from opts applied, ready for any of the Renderer implementations to draw.
+// go-qrcode only supports an on/off quiet zone rather than an arbitrary
+// margin width, so a margin of 0 disables the border and any positive
+// margin keeps the library's standard quiet zone. A logo overlay forces
+// the highest recovery level, since covering part of the code only stays
+// scannable at level H.
+func newQR(content string, opts RenderOptions) (*qrcode.QRCode, error) {
+	level := opts.Level
+	if opts.Logo != nil {
+		level = qrcode.Highest
+	}
+
+	qr, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+
+	qr.DisableBorder = opts.Margin == 0
+	if (opts.ForegroundColor != color.RGBA{}) {
+		qr.ForegroundColor = opts.ForegroundColor
+	}
+	if (opts.BackgroundColor != color.RGBA{}) {
+		qr.BackgroundColor = opts.BackgroundColor
+	}
+
+	return qr, nil
+}
+
+type pngRenderer struct{}
+
+func (pngRenderer) Render(content string, opts RenderOptions, path string) error {
+	qr, err := newQR(content, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Logo == nil {
+		return qr.WriteFile(opts.Size, path)
+	}
+
+	img, err := overlayLogo(qr.Image(opts.Size), opts.Logo)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
+
+type jpegRenderer struct{}
+
+func (jpegRenderer) Render(content string, opts RenderOptions, path string) error {
+	qr, err := newQR(content, opts)
+	if err != nil {
+		return err
+	}
+
+	img := qr.Image(opts.Size)
+	if opts.Logo != nil {
+		if img, err = overlayLogo(img, opts.Logo); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return jpeg.Encode(f, img, &jpeg.Options{Quality: jpeg.DefaultQuality})
+}
+
+type svgRenderer struct{}
+
+func (svgRenderer) Render(content string, opts RenderOptions, path string) error {
+	qr, err := newQR(content, opts)
+	if err != nil {
+		return err
+	}
+
+	bitmap := qr.Bitmap()
+	modules := len(bitmap)
+	if modules == 0 {
+		return fmt.Errorf("qr code has no modules")
+	}
+	size := opts.Size
+	moduleSize := float64(size) / float64(modules)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`+"\n", size, size, size, size)
+	fmt.Fprintf(f, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(qr.BackgroundColor))
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			fmt.Fprintf(f, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s"/>`+"\n",
+				float64(x)*moduleSize, float64(y)*moduleSize, moduleSize, moduleSize, hexColor(qr.ForegroundColor))
+		}
+	}
+	fmt.Fprintln(f, `</svg>`)
+
+	return nil
+}
+
+func hexColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+type pdfRenderer struct{}
+
+func (pdfRenderer) Render(content string, opts RenderOptions, path string) error {
+	qr, err := newQR(content, opts)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, qr.Image(opts.Size)); err != nil {
+		return err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.RegisterImageOptionsReader(content, gofpdf.ImageOptions{ImageType: "PNG"}, &buf)
+	if pdf.Err() {
+		return pdf.Error()
+	}
+
+	pageW, _ := pdf.GetPageSize()
+	side := pageW - 40 // leave a 20mm margin on each side
+	pdf.ImageOptions(content, 20, 20, side, side, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	return pdf.OutputFileAndClose(path)
+}
+
+type asciiRenderer struct{}
+
+func (asciiRenderer) Render(content string, opts RenderOptions, path string) error {
+	qr, err := newQR(content, opts)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(qr.ToString(false))
+	return err
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"sync"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// SheetOptions configures a composited contact sheet of many QR codes, used
+// for print runs (badge/ticket sheets) instead of the usual one-file-per-pin
+// flow. There is deliberately no DPI knob: WritePNG/WritePDF only ever work
+// in raw pixels (PNG) or page-relative mm (PDF), so a DPI setting would have
+// nothing real to size against; a prior version plumbed one through that
+// never affected output, and it was removed rather than left as a no-op.
+type SheetOptions struct {
+	PageSize    string // "A4" or "Letter"
+	CodesPerRow int
+	CellPadding int
+}
+
+func (o SheetOptions) withDefaults() SheetOptions {
+	if o.CodesPerRow <= 0 {
+		o.CodesPerRow = 5
+	}
+	if o.CellPadding <= 0 {
+		o.CellPadding = 20
+	}
+	if o.PageSize == "" {
+		o.PageSize = "A4"
+	}
+	return o
+}
+
+type sheetEntry struct {
+	Label string
+	Image image.Image
+}
+
+// sheetBuilder collects a QR image per pin code from concurrent workers so
+// they can be composited into a single sheet once the batch finishes.
+type sheetBuilder struct {
+	mu      sync.Mutex
+	opts    SheetOptions
+	entries []sheetEntry
+}
+
+func newSheetBuilder(opts SheetOptions) *sheetBuilder {
+	return &sheetBuilder{opts: opts.withDefaults()}
+}
+
+func (s *sheetBuilder) add(label string, img image.Image) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, sheetEntry{Label: label, Image: img})
+}
+
+// WritePNG composites every collected QR code into a single labeled contact
+// sheet, codes-per-row wide, with valueName printed under each code using a
+// built-in bitmap font so no external font file is required.
+func (s *sheetBuilder) WritePNG(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) == 0 {
+		return fmt.Errorf("sheet: no QR codes were collected")
+	}
+
+	// Entries can carry different pixel sizes when per-row "size" overrides
+	// (see input.go) are in play, so the cell is sized off the largest
+	// collected code and every other code is centered within it, rather
+	// than assuming every entry matches the first.
+	codeSize := 0
+	for _, e := range s.entries {
+		if d := e.Image.Bounds().Dx(); d > codeSize {
+			codeSize = d
+		}
+	}
+	const labelHeight = 20
+	cell := codeSize + s.opts.CellPadding*2
+	cols := s.opts.CodesPerRow
+	rows := (len(s.entries) + cols - 1) / cols
+
+	sheet := image.NewRGBA(image.Rect(0, 0, cell*cols, (cell+labelHeight)*rows))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	for i, e := range s.entries {
+		col := i % cols
+		row := i / cols
+		x := col*cell + s.opts.CellPadding
+		y := row*(cell+labelHeight) + s.opts.CellPadding
+
+		b := e.Image.Bounds()
+		offsetX, offsetY := (codeSize-b.Dx())/2, (codeSize-b.Dy())/2
+		draw.Draw(sheet, image.Rect(x+offsetX, y+offsetY, x+offsetX+b.Dx(), y+offsetY+b.Dy()), e.Image, b.Min, draw.Src)
+		drawLabel(sheet, e.Label, x, y+codeSize+14)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, sheet)
+}
+
+func drawLabel(dst draw.Image, label string, x, y int) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(color.Black),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(label)
+}
+
+// WritePDF lays the collected QR codes out as a paginated N×M grid sized to
+// the requested page (A4 or Letter), one contact sheet per page, ready to
+// send straight to a printer.
+func (s *sheetBuilder) WritePDF(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) == 0 {
+		return fmt.Errorf("sheet: no QR codes were collected")
+	}
+
+	pdf := gofpdf.New("P", "mm", s.opts.PageSize, "")
+	pageW, pageH := pdf.GetPageSize()
+	const margin = 10.0
+	cols := s.opts.CodesPerRow
+	cellW := (pageW - margin*2) / float64(cols)
+	cellH := cellW + 8 // room for the label under the code
+	rowsPerPage := int((pageH - margin*2) / cellH)
+	if rowsPerPage < 1 {
+		rowsPerPage = 1
+	}
+	perPage := cols * rowsPerPage
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 8)
+	for i, e := range s.entries {
+		posInPage := i % perPage
+		if i > 0 && posInPage == 0 {
+			pdf.AddPage()
+		}
+
+		col := posInPage % cols
+		row := posInPage / cols
+		x := margin + float64(col)*cellW
+		y := margin + float64(row)*cellH
+		imgSize := cellW - 4
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, e.Image); err != nil {
+			return err
+		}
+		imgName := fmt.Sprintf("qr-%d", i)
+		pdf.RegisterImageOptionsReader(imgName, gofpdf.ImageOptions{ImageType: "PNG"}, &buf)
+		pdf.ImageOptions(imgName, x+2, y+2, imgSize, imgSize, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+		pdf.SetXY(x, y+imgSize+3)
+		pdf.CellFormat(cellW, 5, e.Label, "", 0, "C", false, 0, "")
+	}
+
+	return pdf.OutputFileAndClose(path)
+}
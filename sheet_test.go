@@ -0,0 +1,65 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func solidImage(size int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw := image.NewUniform(c)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, draw.At(x, y))
+		}
+	}
+	return img
+}
+
+// TestWritePNGSizesCellToLargestEntry guards against per-row "size"
+// overrides (input.go) producing entries whose pixel dimensions exceed a
+// cell sized off only the first entry, which would draw later codes past
+// their cell boundary.
+func TestWritePNGSizesCellToLargestEntry(t *testing.T) {
+	s := newSheetBuilder(SheetOptions{CodesPerRow: 2, CellPadding: 10})
+	s.add("small", solidImage(100, color.Black))
+	s.add("big", solidImage(300, color.Black))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sheet.png")
+	if err := s.WritePNG(path); err != nil {
+		t.Fatalf("WritePNG() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open sheet: %v", err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode sheet: %v", err)
+	}
+
+	wantCell := 300 + 10*2
+	wantW := wantCell * 2
+	wantH := wantCell + 20 // one row, plus label height
+	if got := img.Bounds().Dx(); got != wantW {
+		t.Errorf("sheet width = %d, want %d", got, wantW)
+	}
+	if got := img.Bounds().Dy(); got != wantH {
+		t.Errorf("sheet height = %d, want %d", got, wantH)
+	}
+}
+
+func TestWritePNGErrorsWithNoEntries(t *testing.T) {
+	s := newSheetBuilder(SheetOptions{})
+	if err := s.WritePNG(filepath.Join(t.TempDir(), "sheet.png")); err == nil {
+		t.Error("WritePNG() with no entries: want error, got nil")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// verifiableExt reports whether ext is a raster format verifyQRCode can
+// decode. go-qrcode silently mangles non-ASCII UTF-8 payloads under some
+// byte-mode assumptions, so users generating codes with international
+// names/text otherwise have no way to know a code is unscannable short of
+// trying it on a phone.
+func verifiableExt(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	default:
+		return false
+	}
+}
+
+// verifyQRCode decodes the QR code just written to path and compares it
+// byte-for-byte against want, returning an error on any mismatch or
+// decode failure.
+func verifyQRCode(path string, want string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, nil)
+	if err != nil {
+		return fmt.Errorf("verify: decode failed: %w", err)
+	}
+
+	if got := result.GetText(); got != want {
+		return fmt.Errorf("verify: decoded %q, want %q", got, want)
+	}
+
+	return nil
+}